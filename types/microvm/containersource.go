@@ -0,0 +1,120 @@
+/*
+Copyright 2022 Weaveworks.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package microvm
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ImagePullPolicy controls when a container image backing a ContainerFileSource
+// or Volume is (re)pulled, mirroring corev1.PullPolicy.
+type ImagePullPolicy string
+
+const (
+	// PullAlways always pulls the image.
+	PullAlways = ImagePullPolicy("Always")
+	// PullIfNotPresent only pulls the image if it isn't already cached on
+	// the host.
+	PullIfNotPresent = ImagePullPolicy("IfNotPresent")
+	// PullNever never pulls the image; it must already be present on the
+	// host.
+	PullNever = ImagePullPolicy("Never")
+)
+
+// ErrDigestRequired is returned when an ImagePolicy with RequireDigest set
+// is validated against an image with no Digest.
+var ErrDigestRequired = errors.New("image digest is required by cluster policy")
+
+// ErrMutableTag is returned when an image uses a mutable tag, such as
+// ":latest" or no tag at all, and a digest-pinning policy is in effect.
+var ErrMutableTag = errors.New("image uses a mutable tag, which is disallowed when digests are required")
+
+// ErrInvalidDigest is returned when Digest is set but is not a well formed
+// "sha256:<hex>" reference.
+var ErrInvalidDigest = errors.New("digest must be a sha256 hex digest, e.g. sha256:<64 hex chars>")
+
+var digestPattern = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+// ImagePolicy is a supply-chain policy governing how the kernel, initrd,
+// rootfs and any additional volume images referenced by a VMSpec may be
+// specified. It is cluster-wide, rather than per-VMSpec, so that operators
+// can enforce digest pinning across every tenant without relying on each
+// microvm author to opt in individually.
+type ImagePolicy struct {
+	// RequireDigest rejects any image reference that isn't pinned to a
+	// sha256 digest.
+	// +optional
+	RequireDigest bool `json:"requireDigest,omitempty"`
+}
+
+func validateImageRef(image, digest string, policy ImagePolicy) error {
+	if digest != "" && !digestPattern.MatchString(digest) {
+		return fmt.Errorf("%w: %q", ErrInvalidDigest, digest)
+	}
+
+	if !policy.RequireDigest {
+		return nil
+	}
+
+	if digest == "" {
+		return fmt.Errorf("%w: %q", ErrDigestRequired, image)
+	}
+
+	if isMutableTag(image) {
+		return fmt.Errorf("%w: %q", ErrMutableTag, image)
+	}
+
+	return nil
+}
+
+// isMutableTag reports whether image is tagged ":latest" or carries no tag
+// at all, which defaults to "latest".
+func isMutableTag(image string) bool {
+	ref := image
+	if idx := strings.LastIndex(image, "@"); idx != -1 {
+		ref = image[:idx]
+	}
+
+	lastColon := strings.LastIndex(ref, ":")
+	lastSlash := strings.LastIndex(ref, "/")
+
+	if lastColon == -1 || lastColon < lastSlash {
+		return true
+	}
+
+	return ref[lastColon+1:] == "latest"
+}
+
+// Validate checks that the ContainerFileSource satisfies the given cluster
+// ImagePolicy. It is intended to be called from the embedding CRD's
+// webhook.Validator implementation (ValidateCreate/ValidateUpdate) in the
+// consuming controller, alongside the cluster's resolved ImagePolicy; this
+// package defines no top-level CRD object and so registers no webhook
+// itself.
+func (c ContainerFileSource) Validate(policy ImagePolicy) error {
+	return validateImageRef(c.Image, c.Digest, policy)
+}
+
+// Validate checks that the Volume's image reference satisfies the given
+// cluster ImagePolicy. See ContainerFileSource.Validate for how this is
+// expected to be wired into a webhook.
+func (v Volume) Validate(policy ImagePolicy) error {
+	return validateImageRef(v.Image, v.Digest, policy)
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2022 Weaveworks.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package microvm
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestIsMutableTag(t *testing.T) {
+	cases := []struct {
+		image string
+		want  bool
+	}{
+		{image: "example.com/repo/image:latest", want: true},
+		{image: "example.com/repo/image", want: true},
+		{image: "example.com/repo/image:v1.2.3", want: false},
+		{image: "example.com:5000/repo/image:v1.2.3", want: false},
+		{image: "example.com/repo/image@sha256:abc", want: true},
+	}
+
+	for _, tc := range cases {
+		if got := isMutableTag(tc.image); got != tc.want {
+			t.Errorf("isMutableTag(%q) = %v, want %v", tc.image, got, tc.want)
+		}
+	}
+}
+
+func TestContainerFileSourceValidate(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("a", 64)
+
+	cases := []struct {
+		name    string
+		source  ContainerFileSource
+		policy  ImagePolicy
+		wantErr error
+	}{
+		{
+			name:   "no policy, no digest is fine",
+			source: ContainerFileSource{Image: "example.com/repo/image:latest"},
+		},
+		{
+			name:    "digest required but absent",
+			source:  ContainerFileSource{Image: "example.com/repo/image:v1"},
+			policy:  ImagePolicy{RequireDigest: true},
+			wantErr: ErrDigestRequired,
+		},
+		{
+			name:    "digest required, mutable tag rejected",
+			source:  ContainerFileSource{Image: "example.com/repo/image:latest", Digest: digest},
+			policy:  ImagePolicy{RequireDigest: true},
+			wantErr: ErrMutableTag,
+		},
+		{
+			name:   "digest required and satisfied",
+			source: ContainerFileSource{Image: "example.com/repo/image:v1", Digest: digest},
+			policy: ImagePolicy{RequireDigest: true},
+		},
+		{
+			name:    "malformed digest",
+			source:  ContainerFileSource{Image: "example.com/repo/image:v1", Digest: "sha256:not-hex"},
+			wantErr: ErrInvalidDigest,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.source.Validate(tc.policy)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Validate() error = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
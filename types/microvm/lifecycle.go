@@ -0,0 +1,59 @@
+/*
+Copyright 2022 Weaveworks.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package microvm
+
+const (
+	// AutoStartCapability indicates the provider starts a microvm itself as
+	// part of handling the Create call, so the controller does not need to
+	// issue a separate Start.
+	AutoStartCapability = Capability("AutoStart")
+)
+
+// Has returns true if the host has advertised the given capability.
+func (h Host) Has(capability Capability) bool {
+	for _, c := range h.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+
+	return false
+}
+
+// LifecyclePolicy expresses whether a controller reconciling a microvm
+// against a given Host must explicitly call Start after Create.
+type LifecyclePolicy string
+
+const (
+	// LifecyclePolicyAutoStart indicates the provider starts the microvm
+	// itself; the controller should not call Start.
+	LifecyclePolicyAutoStart = LifecyclePolicy("AutoStart")
+	// LifecyclePolicyExplicitStart indicates the controller must call Start
+	// after Create to bring the microvm to VMStateRunning.
+	LifecyclePolicyExplicitStart = LifecyclePolicy("ExplicitStart")
+)
+
+// LifecyclePolicyFor derives the LifecyclePolicy that a controller should
+// follow when reconciling a microvm against host, based on whether host
+// advertises AutoStartCapability.
+func LifecyclePolicyFor(host Host) LifecyclePolicy {
+	if host.Has(AutoStartCapability) {
+		return LifecyclePolicyAutoStart
+	}
+
+	return LifecyclePolicyExplicitStart
+}
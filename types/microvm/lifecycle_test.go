@@ -0,0 +1,82 @@
+/*
+Copyright 2022 Weaveworks.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package microvm
+
+import "testing"
+
+func TestVMStateCanTransitionTo(t *testing.T) {
+	cases := []struct {
+		name string
+		from VMState
+		to   VMState
+		want bool
+	}{
+		{name: "pending to creating is valid", from: VMStatePending, to: VMStateCreating, want: true},
+		{name: "running to creating is invalid", from: VMStateRunning, to: VMStateCreating, want: false},
+		{name: "deleting to deleted is valid", from: VMStateDeleting, to: VMStateDeleted, want: true},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.from.CanTransitionTo(tc.to); got != tc.want {
+				t.Fatalf("%s.CanTransitionTo(%s) = %v, want %v", tc.from, tc.to, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestVMStateIsTerminal(t *testing.T) {
+	cases := []struct {
+		state VMState
+		want  bool
+	}{
+		{state: VMStateDeleted, want: true},
+		{state: VMStateFailed, want: true},
+		{state: VMStateRunning, want: false},
+	}
+
+	for _, tc := range cases {
+		if got := tc.state.IsTerminal(); got != tc.want {
+			t.Fatalf("%s.IsTerminal() = %v, want %v", tc.state, got, tc.want)
+		}
+	}
+}
+
+func TestHostHas(t *testing.T) {
+	host := Host{Capabilities: []Capability{AutoStartCapability}}
+
+	if !host.Has(AutoStartCapability) {
+		t.Fatal("Has(AutoStartCapability) = false, want true")
+	}
+
+	if host.Has(MetadataServiceCapability) {
+		t.Fatal("Has(MetadataServiceCapability) = true, want false")
+	}
+}
+
+func TestLifecyclePolicyFor(t *testing.T) {
+	autoStart := Host{Capabilities: []Capability{AutoStartCapability}}
+	if got := LifecyclePolicyFor(autoStart); got != LifecyclePolicyAutoStart {
+		t.Fatalf("LifecyclePolicyFor() = %v, want %v", got, LifecyclePolicyAutoStart)
+	}
+
+	manual := Host{}
+	if got := LifecyclePolicyFor(manual); got != LifecyclePolicyExplicitStart {
+		t.Fatalf("LifecyclePolicyFor() = %v, want %v", got, LifecyclePolicyExplicitStart)
+	}
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2022 Weaveworks.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package microvm
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+const (
+	// DefaultMMDSAddress is the link-local address flintlock exposes the
+	// metadata service on inside the microvm, matching the well-known
+	// cloud-init/EC2 MMDS address.
+	DefaultMMDSAddress = "169.254.169.254"
+	// DefaultMMDSInterface is the guest network interface that the metadata
+	// service is reachable from when no interface is explicitly configured.
+	DefaultMMDSInterface = "eth0"
+	// DefaultMMDSSessionTokenTTLSeconds is the token TTL requested when
+	// MetadataService.SessionTokenTTLSeconds is unset.
+	DefaultMMDSSessionTokenTTLSeconds = 21600
+
+	// MMDSTokenPath is the path a guest PUTs to in order to acquire an MMDS
+	// v2 session token.
+	MMDSTokenPath = "/latest/api/token"
+	// MMDSTokenTTLHeader is the request header that carries the requested
+	// token TTL, in seconds, on the token acquisition PUT.
+	MMDSTokenTTLHeader = "X-metadata-token-ttl-seconds" //nolint:gosec // header name, not a credential
+	// MMDSTokenHeader is the request header that must carry the
+	// previously-acquired session token on every subsequent MMDS GET.
+	MMDSTokenHeader = "X-metadata-token" //nolint:gosec // header name, not a credential
+)
+
+// ErrAmbiguousDataSource is returned when a DataSource sets more than one
+// of Inline, ConfigMapRef, SecretRef or ContainerSource.
+var ErrAmbiguousDataSource = errors.New("dataSource must set only one of inline, configMapRef, secretRef or containerSource, but set more than one")
+
+// ErrEmptyDataSource is returned when a DataSource sets none of Inline,
+// ConfigMapRef, SecretRef or ContainerSource.
+var ErrEmptyDataSource = errors.New("dataSource must set exactly one of inline, configMapRef, secretRef or containerSource")
+
+// Capability represents a feature that a provider (i.e. flintlock) may or
+// may not support. Consumers should check Host.Has before relying on
+// behaviour that is gated behind one.
+type Capability string
+
+const (
+	// MetadataServiceCapability indicates the provider supports injecting
+	// cloud-init style data via Firecracker's MMDS.
+	MetadataServiceCapability = Capability("MetadataService")
+)
+
+// MetadataService configures the cloud-init/ignition style data that will be
+// delivered to the microvm over Firecracker's MMDS v2. If the provider does
+// not advertise MetadataServiceCapability the controller should skip
+// injection rather than fail, since SSH key injection remains the fallback
+// bootstrap mechanism.
+type MetadataService struct {
+	// Address is the link-local address to expose MMDS on inside the
+	// microvm. Defaults to DefaultMMDSAddress.
+	// +optional
+	Address string `json:"address,omitempty"`
+	// Interface is the guest network interface MMDS is reachable from.
+	// Defaults to DefaultMMDSInterface.
+	// +optional
+	Interface string `json:"interface,omitempty"`
+	// SessionTokenTTLSeconds is the TTL, in seconds, requested for an MMDS
+	// v2 session token. Defaults to DefaultMMDSSessionTokenTTLSeconds.
+	// +optional
+	// +kubebuilder:validation:Minimum:=1
+	SessionTokenTTLSeconds int64 `json:"sessionTokenTTLSeconds,omitempty"`
+	// UserData is the cloud-init user-data to expose over MMDS.
+	// +optional
+	UserData *DataSource `json:"userData,omitempty"`
+	// MetaData is the cloud-init meta-data to expose over MMDS.
+	// +optional
+	MetaData *DataSource `json:"metaData,omitempty"`
+	// NetworkConfig is the cloud-init network-config to expose over MMDS.
+	// +optional
+	NetworkConfig *DataSource `json:"networkConfig,omitempty"`
+	// VendorData is the cloud-init vendor-data to expose over MMDS.
+	// +optional
+	VendorData *DataSource `json:"vendorData,omitempty"`
+}
+
+// address returns Address, or DefaultMMDSAddress if it isn't set.
+func (m MetadataService) address() string {
+	if m.Address != "" {
+		return m.Address
+	}
+
+	return DefaultMMDSAddress
+}
+
+// iface returns Interface, or DefaultMMDSInterface if it isn't set.
+func (m MetadataService) iface() string {
+	if m.Interface != "" {
+		return m.Interface
+	}
+
+	return DefaultMMDSInterface
+}
+
+// tokenTTLSeconds returns SessionTokenTTLSeconds, or
+// DefaultMMDSSessionTokenTTLSeconds if it isn't set.
+func (m MetadataService) tokenTTLSeconds() int64 {
+	if m.SessionTokenTTLSeconds != 0 {
+		return m.SessionTokenTTLSeconds
+	}
+
+	return DefaultMMDSSessionTokenTTLSeconds
+}
+
+// MMDSTokenRequest describes the PUT a guest must issue, before any GET,
+// to acquire an MMDS v2 session token.
+type MMDSTokenRequest struct {
+	// Address is the MMDS endpoint to PUT the token request to.
+	Address string
+	// Interface is the guest network interface to issue the request from.
+	Interface string
+	// Path is MMDSTokenPath.
+	Path string
+	// TTLSeconds is the value to send in the MMDSTokenTTLHeader header.
+	TTLSeconds int64
+}
+
+// TokenRequest returns the MMDS v2 session-token request a guest must make
+// against this MetadataService before it can GET any of UserData, MetaData,
+// NetworkConfig or VendorData.
+func (m MetadataService) TokenRequest() MMDSTokenRequest {
+	return MMDSTokenRequest{
+		Address:    m.address(),
+		Interface:  m.iface(),
+		Path:       MMDSTokenPath,
+		TTLSeconds: m.tokenTTLSeconds(),
+	}
+}
+
+// ResolvedMetadataService carries the resolved content of each configured
+// DataSource on a MetadataService. This package has no Kubernetes/registry
+// client of its own, so callers must resolve ConfigMapRef, SecretRef and
+// ContainerSource themselves before calling ToMMDSPayload.
+type ResolvedMetadataService struct {
+	UserData      string
+	MetaData      string
+	NetworkConfig string
+	VendorData    string
+}
+
+// ToMMDSPayload renders the exact JSON shape flintlock forwards to
+// Firecracker's MMDS v2 PUT /mmds API: a flat object keyed by the
+// cloud-init NoCloud datasource field names, containing only the fields
+// that were actually configured on m.
+func (m MetadataService) ToMMDSPayload(resolved ResolvedMetadataService) ([]byte, error) {
+	payload := map[string]string{}
+
+	if m.UserData != nil {
+		payload["user-data"] = resolved.UserData
+	}
+
+	if m.MetaData != nil {
+		payload["meta-data"] = resolved.MetaData
+	}
+
+	if m.NetworkConfig != nil {
+		payload["network-config"] = resolved.NetworkConfig
+	}
+
+	if m.VendorData != nil {
+		payload["vendor-data"] = resolved.VendorData
+	}
+
+	return json.Marshal(payload)
+}
+
+// DataSource represents a single piece of MMDS content. Exactly one of
+// Inline, ConfigMapRef, SecretRef or ContainerSource must be set.
+type DataSource struct {
+	// Inline is the literal content to use.
+	// +optional
+	Inline *string `json:"inline,omitempty"`
+	// ConfigMapRef sources the content from a key in a ConfigMap.
+	// +optional
+	ConfigMapRef *ConfigMapKeyRef `json:"configMapRef,omitempty"`
+	// SecretRef sources the content from a key in a Secret.
+	// +optional
+	SecretRef *SecretKeyRef `json:"secretRef,omitempty"`
+	// ContainerSource sources the content from a file in a container image.
+	// +optional
+	ContainerSource *ContainerFileSource `json:"containerSource,omitempty"`
+}
+
+// Validate checks that exactly one of Inline, ConfigMapRef, SecretRef or
+// ContainerSource is set. It is intended to be called from the embedding
+// CRD's webhook.Validator implementation in the consuming controller; this
+// package defines no top-level CRD object and so registers no webhook
+// itself.
+func (d DataSource) Validate() error {
+	set := 0
+
+	for _, isSet := range []bool{d.Inline != nil, d.ConfigMapRef != nil, d.SecretRef != nil, d.ContainerSource != nil} {
+		if isSet {
+			set++
+		}
+	}
+
+	switch {
+	case set == 0:
+		return ErrEmptyDataSource
+	case set > 1:
+		return ErrAmbiguousDataSource
+	default:
+		return nil
+	}
+}
+
+// ConfigMapKeyRef refers to a key in a ConfigMap in the same namespace as
+// the owning resource.
+type ConfigMapKeyRef struct {
+	// Name is the name of the ConfigMap.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// Key is the key within the ConfigMap to use. Defaults to the
+	// MetadataService field name (e.g. "userData") if not supplied.
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// SecretKeyRef refers to a key in a Secret in the same namespace as the
+// owning resource.
+type SecretKeyRef struct {
+	// Name is the name of the Secret.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// Key is the key within the Secret to use. Defaults to the
+	// MetadataService field name (e.g. "userData") if not supplied.
+	// +optional
+	Key string `json:"key,omitempty"`
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2022 Weaveworks.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package microvm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMetadataServiceTokenRequest(t *testing.T) {
+	cases := []struct {
+		name    string
+		service MetadataService
+		want    MMDSTokenRequest
+	}{
+		{
+			name:    "defaults",
+			service: MetadataService{},
+			want: MMDSTokenRequest{
+				Address:    DefaultMMDSAddress,
+				Interface:  DefaultMMDSInterface,
+				Path:       MMDSTokenPath,
+				TTLSeconds: DefaultMMDSSessionTokenTTLSeconds,
+			},
+		},
+		{
+			name:    "overridden",
+			service: MetadataService{Address: "10.0.0.1", Interface: "eth1", SessionTokenTTLSeconds: 60},
+			want: MMDSTokenRequest{
+				Address:    "10.0.0.1",
+				Interface:  "eth1",
+				Path:       MMDSTokenPath,
+				TTLSeconds: 60,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.service.TokenRequest()
+			if got != tc.want {
+				t.Fatalf("TokenRequest() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMetadataServiceToMMDSPayload(t *testing.T) {
+	inline := "hello"
+	service := MetadataService{
+		UserData: &DataSource{Inline: &inline},
+	}
+
+	payload, err := service.ToMMDSPayload(ResolvedMetadataService{UserData: "hello", MetaData: "unused"})
+	if err != nil {
+		t.Fatalf("ToMMDSPayload() error = %v", err)
+	}
+
+	want := `{"user-data":"hello"}`
+	if string(payload) != want {
+		t.Fatalf("ToMMDSPayload() = %s, want %s", payload, want)
+	}
+}
+
+func TestDataSourceValidate(t *testing.T) {
+	inline := "hello"
+
+	cases := []struct {
+		name    string
+		source  DataSource
+		wantErr error
+	}{
+		{
+			name:   "exactly one set",
+			source: DataSource{Inline: &inline},
+		},
+		{
+			name:    "none set",
+			source:  DataSource{},
+			wantErr: ErrEmptyDataSource,
+		},
+		{
+			name: "two set",
+			source: DataSource{
+				Inline:       &inline,
+				ConfigMapRef: &ConfigMapKeyRef{Name: "cm"},
+			},
+			wantErr: ErrAmbiguousDataSource,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.source.Validate()
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Validate() error = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
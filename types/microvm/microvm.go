@@ -65,6 +65,19 @@ type VMSpec struct {
 	// Labels allow you to include extra data on the Microvm
 	// +optional
 	Labels map[string]string `json:"labels"`
+
+	// MetadataService configures the cloud-init/ignition data to deliver to
+	// the microvm over Firecracker's MMDS v2. This is only honoured by
+	// providers that advertise MetadataServiceCapability; SSH key injection
+	// via AuthorizedKeys remains available regardless.
+	// +optional
+	MetadataService *MetadataService `json:"metadataService,omitempty"`
+
+	// LifecyclePolicy records whether the controller must explicitly call
+	// Start after Create to bring this microvm up. It should be derived
+	// from the target Host via LifecyclePolicyFor rather than set by users.
+	// +optional
+	LifecyclePolicy LifecyclePolicy `json:"lifecyclePolicy,omitempty"`
 }
 
 // ContainerFileSource represents a file coming from a container image.
@@ -75,6 +88,19 @@ type ContainerFileSource struct {
 	// Filename is the name of the file in the container to use.
 	// +optional
 	Filename string `json:"filename,omitempty"`
+	// ImagePullPolicy controls when Image is (re)pulled.
+	// +optional
+	// +kubebuilder:validation:Enum=Always;IfNotPresent;Never
+	ImagePullPolicy ImagePullPolicy `json:"imagePullPolicy,omitempty"`
+	// ImagePullSecretRef references a Secret of type
+	// kubernetes.io/dockerconfigjson used to pull Image.
+	// +optional
+	ImagePullSecretRef *SecretRef `json:"imagePullSecretRef,omitempty"`
+	// Digest pins Image to a specific content digest, e.g.
+	// "sha256:<64 hex chars>". Required when the cluster's ImagePolicy sets
+	// RequireDigest.
+	// +optional
+	Digest string `json:"digest,omitempty"`
 }
 
 // Volume represents a volume to be attached to a microvm.
@@ -93,6 +119,23 @@ type Volume struct {
 	// This will only be applied to additional volumes.
 	// +optional
 	MountPoint string `json:"mountPoint,omitempty"`
+	// RateLimiter throttles the bandwidth and/or operations available to
+	// this volume's reads and writes.
+	// +optional
+	RateLimiter *RateLimiter `json:"rateLimiter,omitempty"`
+	// ImagePullPolicy controls when Image is (re)pulled.
+	// +optional
+	// +kubebuilder:validation:Enum=Always;IfNotPresent;Never
+	ImagePullPolicy ImagePullPolicy `json:"imagePullPolicy,omitempty"`
+	// ImagePullSecretRef references a Secret of type
+	// kubernetes.io/dockerconfigjson used to pull Image.
+	// +optional
+	ImagePullSecretRef *SecretRef `json:"imagePullSecretRef,omitempty"`
+	// Digest pins Image to a specific content digest, e.g.
+	// "sha256:<64 hex chars>". Required when the cluster's ImagePolicy sets
+	// RequireDigest.
+	// +optional
+	Digest string `json:"digest,omitempty"`
 }
 
 // IfaceType is a type representing the network interface types.
@@ -120,6 +163,14 @@ type NetworkInterface struct {
 	// Address is an optional IP address to assign to this interface. If not supplied then DHCP will be used.
 	// +optional
 	Address string `json:"address,omitempty"`
+	// RxRateLimiter throttles the bandwidth and/or operations available to
+	// this interface's received traffic.
+	// +optional
+	RxRateLimiter *RateLimiter `json:"rxRateLimiter,omitempty"`
+	// TxRateLimiter throttles the bandwidth and/or operations available to
+	// this interface's transmitted traffic.
+	// +optional
+	TxRateLimiter *RateLimiter `json:"txRateLimiter,omitempty"`
 }
 
 // VMState is a type that represents the state of a microvm.
@@ -128,16 +179,51 @@ type VMState string
 var (
 	// VMStatePending indicates the microvm hasn't been started.
 	VMStatePending = VMState("pending")
+	// VMStateCreating indicates the microvm create call has been accepted by
+	// the provider but has not yet finished (flintlock's PendingState).
+	VMStateCreating = VMState("creating")
 	// VMStateRunning indicates the microvm is running.
 	VMStateRunning = VMState("running")
 	// VMStateFailed indicates the microvm has failed.
 	VMStateFailed = VMState("failed")
+	// VMStateDeleting indicates the microvm delete call has been accepted by
+	// the provider but the microvm has not yet been removed.
+	VMStateDeleting = VMState("deleting")
 	// VMStateDeleted indicates the microvm has been deleted.
 	VMStateDeleted = VMState("deleted")
 	// VMStateUnknown indicates the microvm is in an state that is unknown/supported by CAPMVM.
 	VMStateUnknown = VMState("unknown")
+
+	// vmStateTransitions enumerates the states reachable from each VMState,
+	// mirroring flintlock's PendingState/CreatedState/FailedState/DeletingState
+	// lifecycle.
+	vmStateTransitions = map[VMState][]VMState{
+		VMStatePending:  {VMStateCreating, VMStateFailed, VMStateDeleting},
+		VMStateCreating: {VMStateRunning, VMStateFailed, VMStateDeleting},
+		VMStateRunning:  {VMStateFailed, VMStateDeleting},
+		VMStateDeleting: {VMStateDeleted, VMStateFailed},
+		VMStateUnknown:  {VMStatePending, VMStateCreating, VMStateRunning, VMStateFailed, VMStateDeleting, VMStateDeleted},
+	}
 )
 
+// IsTerminal returns true if the microvm will not transition to any other
+// state without external intervention (e.g. recreation).
+func (s VMState) IsTerminal() bool {
+	return s == VMStateDeleted || s == VMStateFailed
+}
+
+// CanTransitionTo returns true if it is valid for the microvm to move from
+// state s to next.
+func (s VMState) CanTransitionTo(next VMState) bool {
+	for _, candidate := range vmStateTransitions[s] {
+		if candidate == next {
+			return true
+		}
+	}
+
+	return false
+}
+
 type Host struct {
 	// Name is an optional name for the host.
 	// +optional
@@ -146,6 +232,29 @@ type Host struct {
 	// including the port.
 	// +kubebuilder:validation:Required
 	Endpoint string `json:"endpoint"`
+	// Labels allow this host to be matched by a PlacementSpec's
+	// LabelSelector.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// ControlPlaneAllowed indicates whether control plane microvms may be
+	// scheduled onto this host.
+	// +optional
+	ControlPlaneAllowed bool `json:"controlPlaneAllowed,omitempty"`
+	// FailureDomain is the failure domain this host belongs to, allowing
+	// the scheduler to spread microvms across hosts.
+	// +optional
+	FailureDomain string `json:"failureDomain,omitempty"`
+	// Capabilities lists the features this host's provider has been probed
+	// to support. See Host.Has.
+	// +optional
+	Capabilities []Capability `json:"capabilities,omitempty"`
+	// TLSConfig configures how to verify this host's certificate and,
+	// optionally, how to present a client certificate for mTLS.
+	// +optional
+	TLSConfig *TLSConfig `json:"tlsConfig,omitempty"`
+	// BasicAuth configures HTTP basic auth credentials for this host.
+	// +optional
+	BasicAuth *BasicAuth `json:"basicAuth,omitempty"`
 }
 
 type SSHPublicKey struct {
@@ -0,0 +1,223 @@
+/*
+Copyright 2022 Weaveworks.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package microvm
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNoStaticPool is returned when a PlacementSpec has no StaticPool
+// configured; today StaticPool is the only source of candidate hosts.
+var ErrNoStaticPool = errors.New("placement requires staticPool to be set")
+
+// ErrNoMatchingHost is returned by SelectHost when no host in the pool
+// satisfies the requested placement.
+var ErrNoMatchingHost = errors.New("no host matched the requested placement")
+
+// SecretRef refers to a Secret, in its entirety, in the same namespace as
+// the owning resource.
+type SecretRef struct {
+	// Name is the name of the Secret.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+}
+
+// PlacementSpec describes how a microvm should be scheduled onto a Host.
+// It deliberately carries no namespace/owner references of its own so
+// that it can be embedded as-is into MicrovmCluster's spec (defined
+// outside this package, where those references already live).
+type PlacementSpec struct {
+	// StaticPool is the fixed, explicitly listed set of hosts that microvms
+	// may be scheduled onto, mirroring the staticPool concept used by
+	// CAPMVM.
+	// +kubebuilder:validation:Required
+	StaticPool *StaticPool `json:"staticPool"`
+
+	// LabelSelector, if set, restricts scheduling to hosts in StaticPool
+	// whose Labels match every entry here. It is merged with the
+	// machineLabels passed to SelectHost — both must be satisfied by the
+	// chosen Host's Labels. If both are unset any host in the pool may be
+	// selected.
+	// +optional
+	LabelSelector map[string]string `json:"labelSelector,omitempty"`
+}
+
+// Validate checks that the PlacementSpec is well formed. It is intended to
+// be called from the embedding CRD's webhook.Validator implementation
+// (ValidateCreate/ValidateUpdate) in the consuming controller; this
+// package defines no top-level CRD object and so registers no webhook
+// itself.
+func (p PlacementSpec) Validate() error {
+	if p.StaticPool == nil {
+		return ErrNoStaticPool
+	}
+
+	return nil
+}
+
+// StaticPool is a fixed, explicitly listed set of hosts that microvms may
+// be scheduled onto.
+type StaticPool struct {
+	// Hosts is the list of hosts in the pool.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems:=1
+	Hosts []Host `json:"hosts"`
+
+	// BasicAuthSecretRef references a Secret containing basic auth
+	// credentials used to authenticate with every host in the pool.
+	// +optional
+	BasicAuthSecretRef *SecretRef `json:"basicAuthSecretRef,omitempty"`
+
+	// TLSSecretRef references a Secret containing the TLS client
+	// credentials used to authenticate with every host in the pool.
+	// +optional
+	TLSSecretRef *SecretRef `json:"tlsSecretRef,omitempty"`
+}
+
+// SelectOption customises how SelectHost narrows down candidate hosts.
+type SelectOption func(*selectOptions)
+
+type selectOptions struct {
+	controlPlane        bool
+	failureDomainCounts map[string]int
+}
+
+// WithControlPlane restricts selection to hosts with ControlPlaneAllowed
+// set, for use when scheduling a control plane microvm.
+func WithControlPlane() SelectOption {
+	return func(o *selectOptions) {
+		o.controlPlane = true
+	}
+}
+
+// WithFailureDomainCounts spreads selection across FailureDomains by
+// preferring the candidate host whose FailureDomain has the lowest count
+// in counts (callers typically pass in how many microvms they've already
+// placed per failure domain). Hosts with no recorded FailureDomain, or
+// whose FailureDomain isn't present in counts, are treated as count zero.
+func WithFailureDomainCounts(counts map[string]int) SelectOption {
+	return func(o *selectOptions) {
+		o.failureDomainCounts = counts
+	}
+}
+
+// SelectHost picks the Host that a microvm with the given labels should be
+// placed on. Candidates are restricted to hosts in StaticPool.Hosts whose
+// Labels are a superset of both spec.LabelSelector and machineLabels; if
+// neither is set every host in the pool is a candidate. WithControlPlane
+// and WithFailureDomainCounts further narrow and order that candidate set.
+func SelectHost(spec PlacementSpec, machineLabels map[string]string, opts ...SelectOption) (*Host, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+
+	options := selectOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	selector := mergeSelectors(spec.LabelSelector, machineLabels)
+
+	candidates := candidateHosts(spec, selector, options)
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("%w: selector %v", ErrNoMatchingHost, selector)
+	}
+
+	if options.failureDomainCounts == nil {
+		host := candidates[0]
+
+		return &host, nil
+	}
+
+	host := leastUsedFailureDomain(candidates, options.failureDomainCounts)
+
+	return &host, nil
+}
+
+// mergeSelectors combines a PlacementSpec's LabelSelector with the calling
+// machine's own labels into a single selector a candidate Host's Labels
+// must satisfy. Where both set the same key, machineLabels' value is kept.
+func mergeSelectors(labelSelector, machineLabels map[string]string) map[string]string {
+	if len(labelSelector) == 0 {
+		return machineLabels
+	}
+
+	if len(machineLabels) == 0 {
+		return labelSelector
+	}
+
+	merged := make(map[string]string, len(labelSelector)+len(machineLabels))
+
+	for k, v := range labelSelector {
+		merged[k] = v
+	}
+
+	for k, v := range machineLabels {
+		merged[k] = v
+	}
+
+	return merged
+}
+
+func candidateHosts(spec PlacementSpec, selector map[string]string, options selectOptions) []Host {
+	var candidates []Host
+
+	for _, host := range spec.StaticPool.Hosts {
+		if options.controlPlane && !host.ControlPlaneAllowed {
+			continue
+		}
+
+		if len(selector) > 0 && !hostLabelsMatch(host.Labels, selector) {
+			continue
+		}
+
+		// selector carries machineLabels' value for any key both set, so
+		// LabelSelector is checked on its own as well.
+		if !hostLabelsMatch(host.Labels, spec.LabelSelector) {
+			continue
+		}
+
+		candidates = append(candidates, host)
+	}
+
+	return candidates
+}
+
+func leastUsedFailureDomain(hosts []Host, counts map[string]int) Host {
+	best := hosts[0]
+	bestCount := counts[best.FailureDomain]
+
+	for _, host := range hosts[1:] {
+		if count := counts[host.FailureDomain]; count < bestCount {
+			best = host
+			bestCount = count
+		}
+	}
+
+	return best
+}
+
+func hostLabelsMatch(hostLabels, selector map[string]string) bool {
+	for k, v := range selector {
+		if hostLabels[k] != v {
+			return false
+		}
+	}
+
+	return true
+}
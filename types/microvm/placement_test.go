@@ -0,0 +1,110 @@
+/*
+Copyright 2022 Weaveworks.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package microvm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSelectHost(t *testing.T) {
+	hosts := []Host{
+		{Name: "a", Labels: map[string]string{"zone": "1"}, FailureDomain: "az1"},
+		{Name: "b", Labels: map[string]string{"zone": "2"}, ControlPlaneAllowed: true, FailureDomain: "az2"},
+	}
+	spec := PlacementSpec{StaticPool: &StaticPool{Hosts: hosts}}
+
+	t.Run("no selector returns first host", func(t *testing.T) {
+		host, err := SelectHost(spec, nil)
+		if err != nil {
+			t.Fatalf("SelectHost() error = %v", err)
+		}
+
+		if host.Name != "a" {
+			t.Fatalf("SelectHost() = %q, want %q", host.Name, "a")
+		}
+	})
+
+	t.Run("label selector matches host b", func(t *testing.T) {
+		spec := PlacementSpec{StaticPool: &StaticPool{Hosts: hosts}, LabelSelector: map[string]string{"zone": "2"}}
+
+		host, err := SelectHost(spec, nil)
+		if err != nil {
+			t.Fatalf("SelectHost() error = %v", err)
+		}
+
+		if host.Name != "b" {
+			t.Fatalf("SelectHost() = %q, want %q", host.Name, "b")
+		}
+	})
+
+	t.Run("control plane filters to allowed hosts", func(t *testing.T) {
+		host, err := SelectHost(spec, nil, WithControlPlane())
+		if err != nil {
+			t.Fatalf("SelectHost() error = %v", err)
+		}
+
+		if host.Name != "b" {
+			t.Fatalf("SelectHost() = %q, want %q", host.Name, "b")
+		}
+	})
+
+	t.Run("failure domain counts prefer least used", func(t *testing.T) {
+		host, err := SelectHost(spec, nil, WithFailureDomainCounts(map[string]int{"az1": 3, "az2": 0}))
+		if err != nil {
+			t.Fatalf("SelectHost() error = %v", err)
+		}
+
+		if host.Name != "b" {
+			t.Fatalf("SelectHost() = %q, want %q", host.Name, "b")
+		}
+	})
+
+	t.Run("machineLabels alone restrict the candidate set", func(t *testing.T) {
+		host, err := SelectHost(spec, map[string]string{"zone": "2"})
+		if err != nil {
+			t.Fatalf("SelectHost() error = %v", err)
+		}
+
+		if host.Name != "b" {
+			t.Fatalf("SelectHost() = %q, want %q", host.Name, "b")
+		}
+	})
+
+	t.Run("labelSelector and machineLabels must both be satisfied", func(t *testing.T) {
+		spec := PlacementSpec{StaticPool: &StaticPool{Hosts: hosts}, LabelSelector: map[string]string{"zone": "2"}}
+
+		_, err := SelectHost(spec, map[string]string{"zone": "1"})
+		if !errors.Is(err, ErrNoMatchingHost) {
+			t.Fatalf("SelectHost() error = %v, want %v", err, ErrNoMatchingHost)
+		}
+	})
+
+	t.Run("no match returns ErrNoMatchingHost", func(t *testing.T) {
+		_, err := SelectHost(spec, map[string]string{"zone": "nope"})
+		if !errors.Is(err, ErrNoMatchingHost) {
+			t.Fatalf("SelectHost() error = %v, want %v", err, ErrNoMatchingHost)
+		}
+	})
+
+	t.Run("no static pool returns ErrNoStaticPool", func(t *testing.T) {
+		_, err := SelectHost(PlacementSpec{}, nil)
+		if !errors.Is(err, ErrNoStaticPool) {
+			t.Fatalf("SelectHost() error = %v, want %v", err, ErrNoStaticPool)
+		}
+	})
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2022 Weaveworks.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package microvm
+
+// MicrovmProxy configures an HTTP(S) proxy that transport.DialOptions
+// tunnels its gRPC connection through via HTTP CONNECT, for environments
+// where flintlock sits behind a corporate proxy. No type in this package
+// embeds it, and Host carries no proxy of its own: it is passed to
+// transport.DialOptions separately, so one proxy can serve every host
+// behind a shared network egress path.
+type MicrovmProxy struct {
+	// Endpoint is the address of the proxy, e.g. https://proxy.example.com:3128.
+	// +kubebuilder:validation:Required
+	Endpoint string `json:"endpoint"`
+	// CACertSecretRef references a Secret containing the CA bundle used to
+	// verify the proxy's certificate.
+	// +optional
+	CACertSecretRef *SecretRef `json:"caCertSecretRef,omitempty"`
+	// ClientCertSecretRef references a Secret containing the client
+	// certificate and key used to authenticate with the proxy.
+	// +optional
+	ClientCertSecretRef *SecretRef `json:"clientCertSecretRef,omitempty"`
+}
+
+// TLSConfig configures how a controller should verify a Host's server
+// certificate and optionally present its own client certificate.
+type TLSConfig struct {
+	// CASecretRef references a Secret containing the CA bundle used to
+	// verify the host's certificate.
+	// +optional
+	CASecretRef *SecretRef `json:"caSecretRef,omitempty"`
+	// ClientCertSecretRef references a Secret containing the client
+	// certificate and key used to authenticate with the host (mTLS).
+	// +optional
+	ClientCertSecretRef *SecretRef `json:"clientCertSecretRef,omitempty"`
+	// InsecureSkipVerify disables verification of the host's certificate.
+	// This should only be used for testing.
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// BasicAuth configures HTTP basic auth credentials for a Host.
+type BasicAuth struct {
+	// SecretRef references a Secret containing "username" and "password"
+	// keys.
+	// +kubebuilder:validation:Required
+	SecretRef SecretRef `json:"secretRef"`
+}
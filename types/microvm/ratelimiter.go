@@ -0,0 +1,124 @@
+/*
+Copyright 2022 Weaveworks.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package microvm
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// ErrInvalidTokenBucket is returned when a TokenBucket has a non-positive
+// Size or RefillTimeMs, or a non-positive OneTimeBurst when one is set.
+var ErrInvalidTokenBucket = errors.New("token bucket requires a positive size and refillTimeMs")
+
+// TokenBucket is a Firecracker v1+ token-bucket rate limit, shared by the
+// bandwidth and ops limits on RateLimiter.
+type TokenBucket struct {
+	// Size is the total number of tokens the bucket can hold.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum:=1
+	Size int64 `json:"size"`
+	// OneTimeBurst is the initial size of a token bucket burst, consumed
+	// before the bucket starts refilling at its steady-state rate.
+	// +optional
+	// +kubebuilder:validation:Minimum:=1
+	OneTimeBurst *int64 `json:"oneTimeBurst,omitempty"`
+	// RefillTimeMs is the time, in milliseconds, it takes for the bucket to
+	// refill from empty to Size.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum:=1
+	RefillTimeMs int64 `json:"refillTimeMs"`
+}
+
+// Validate checks that the TokenBucket's parameters are usable.
+func (t TokenBucket) Validate() error {
+	if t.Size <= 0 || t.RefillTimeMs <= 0 {
+		return ErrInvalidTokenBucket
+	}
+
+	if t.OneTimeBurst != nil && *t.OneTimeBurst <= 0 {
+		return ErrInvalidTokenBucket
+	}
+
+	return nil
+}
+
+// firecrackerTokenBucket is the wire shape flintlock forwards to
+// Firecracker's PUT /rate-limiter API, which uses refill_time in
+// milliseconds and snake_case fields.
+type firecrackerTokenBucket struct {
+	Size         int64  `json:"size"`
+	OneTimeBurst *int64 `json:"one_time_burst,omitempty"`
+	RefillTime   int64  `json:"refill_time"`
+}
+
+// RateLimiter is a Firecracker v1+ rate limiter, made up of independent
+// bandwidth (bytes/s) and ops (operations/s) token buckets. Volume uses a
+// single RateLimiter to throttle reads and writes together; NetworkInterface
+// uses one for RX and one for TX.
+type RateLimiter struct {
+	// Bandwidth throttles the number of bytes/s.
+	// +optional
+	Bandwidth *TokenBucket `json:"bandwidth,omitempty"`
+	// Ops throttles the number of operations/s.
+	// +optional
+	Ops *TokenBucket `json:"ops,omitempty"`
+}
+
+// Validate checks that every token bucket configured on the RateLimiter is
+// usable.
+func (r RateLimiter) Validate() error {
+	if r.Bandwidth != nil {
+		if err := r.Bandwidth.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if r.Ops != nil {
+		if err := r.Ops.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MarshalFirecrackerJSON renders the RateLimiter as the exact JSON shape
+// flintlock forwards to Firecracker's rate-limiter API.
+func (r RateLimiter) MarshalFirecrackerJSON() ([]byte, error) {
+	out := struct {
+		Bandwidth *firecrackerTokenBucket `json:"bandwidth,omitempty"`
+		Ops       *firecrackerTokenBucket `json:"ops,omitempty"`
+	}{
+		Bandwidth: toFirecrackerTokenBucket(r.Bandwidth),
+		Ops:       toFirecrackerTokenBucket(r.Ops),
+	}
+
+	return json.Marshal(out)
+}
+
+func toFirecrackerTokenBucket(t *TokenBucket) *firecrackerTokenBucket {
+	if t == nil {
+		return nil
+	}
+
+	return &firecrackerTokenBucket{
+		Size:         t.Size,
+		OneTimeBurst: t.OneTimeBurst,
+		RefillTime:   t.RefillTimeMs,
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2022 Weaveworks.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package microvm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTokenBucketValidate(t *testing.T) {
+	burst := int64(10)
+
+	cases := []struct {
+		name    string
+		bucket  TokenBucket
+		wantErr error
+	}{
+		{
+			name:   "valid",
+			bucket: TokenBucket{Size: 100, RefillTimeMs: 1000, OneTimeBurst: &burst},
+		},
+		{
+			name:    "zero size",
+			bucket:  TokenBucket{Size: 0, RefillTimeMs: 1000},
+			wantErr: ErrInvalidTokenBucket,
+		},
+		{
+			name:    "zero refill time",
+			bucket:  TokenBucket{Size: 100, RefillTimeMs: 0},
+			wantErr: ErrInvalidTokenBucket,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.bucket.Validate()
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Validate() error = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestRateLimiterMarshalFirecrackerJSON(t *testing.T) {
+	burst := int64(5)
+	limiter := RateLimiter{
+		Bandwidth: &TokenBucket{Size: 1024, OneTimeBurst: &burst, RefillTimeMs: 100},
+	}
+
+	got, err := limiter.MarshalFirecrackerJSON()
+	if err != nil {
+		t.Fatalf("MarshalFirecrackerJSON() error = %v", err)
+	}
+
+	want := `{"bandwidth":{"size":1024,"one_time_burst":5,"refill_time":100}}`
+	if string(got) != want {
+		t.Fatalf("MarshalFirecrackerJSON() = %s, want %s", got, want)
+	}
+}
+
+func TestRateLimiterMarshalFirecrackerJSONOmitsUnset(t *testing.T) {
+	got, err := RateLimiter{}.MarshalFirecrackerJSON()
+	if err != nil {
+		t.Fatalf("MarshalFirecrackerJSON() error = %v", err)
+	}
+
+	if string(got) != `{}` {
+		t.Fatalf("MarshalFirecrackerJSON() = %s, want {}", got)
+	}
+}
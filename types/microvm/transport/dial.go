@@ -0,0 +1,291 @@
+/*
+Copyright 2022 Weaveworks.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package transport builds grpc.DialOption sets for connecting to a
+// flintlock Host, so that every caller in the controller fleet ends up
+// with the same TLS/mTLS/basic-auth/proxy behaviour instead of reinventing
+// it.
+package transport
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/weaveworks-liquidmetal/controller-pkg/types/microvm"
+)
+
+// ErrCredentialsRequired is returned when host.TLSConfig, host.BasicAuth or
+// a MicrovmProxy references a Secret but no resolved value for it was
+// supplied. This package has no Kubernetes client of its own, so callers
+// must resolve SecretRefs themselves (e.g. via a client-go Lister) before
+// calling DialOptions.
+var ErrCredentialsRequired = errors.New("transport: a secret reference was not resolved")
+
+// Credentials carries the resolved content of any SecretRefs configured on
+// a microvm.Host and/or microvm.MicrovmProxy, since this package cannot
+// read Secrets itself.
+type Credentials struct {
+	// CACertPEM is the resolved content of Host.TLSConfig.CASecretRef.
+	CACertPEM []byte
+	// ClientCertPEM and ClientKeyPEM are the resolved content of
+	// Host.TLSConfig.ClientCertSecretRef.
+	ClientCertPEM []byte
+	ClientKeyPEM  []byte
+	// BasicAuthUsername and BasicAuthPassword are the resolved content of
+	// Host.BasicAuth.SecretRef.
+	BasicAuthUsername string
+	BasicAuthPassword string
+	// ProxyCACertPEM is the resolved content of
+	// MicrovmProxy.CACertSecretRef.
+	ProxyCACertPEM []byte
+	// ProxyClientCertPEM and ProxyClientKeyPEM are the resolved content of
+	// MicrovmProxy.ClientCertSecretRef.
+	ProxyClientCertPEM []byte
+	ProxyClientKeyPEM  []byte
+}
+
+// DialOptions builds the grpc.DialOption set needed to connect to host,
+// honouring its TLSConfig and BasicAuth. If proxy is non-nil, the
+// connection is tunnelled through it via HTTP CONNECT. creds must contain
+// the resolved content of any SecretRefs host and proxy reference.
+func DialOptions(host microvm.Host, proxy *microvm.MicrovmProxy, creds Credentials) ([]grpc.DialOption, error) {
+	transportCreds, err := transportCredentials(host, creds)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(transportCreds)}
+
+	if host.BasicAuth != nil {
+		if creds.BasicAuthUsername == "" && creds.BasicAuthPassword == "" {
+			return nil, fmt.Errorf("%w: basicAuth", ErrCredentialsRequired)
+		}
+
+		opts = append(opts, grpc.WithPerRPCCredentials(basicAuthCredentials{
+			username: creds.BasicAuthUsername,
+			password: creds.BasicAuthPassword,
+		}))
+	}
+
+	if proxy != nil {
+		dialer, err := proxyContextDialer(*proxy, creds)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, grpc.WithContextDialer(dialer))
+	}
+
+	return opts, nil
+}
+
+func transportCredentials(host microvm.Host, creds Credentials) (credentials.TransportCredentials, error) {
+	if host.TLSConfig == nil {
+		return insecure.NewCredentials(), nil
+	}
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: host.TLSConfig.InsecureSkipVerify, //nolint:gosec // explicit opt-in via TLSConfig
+		MinVersion:         tls.VersionTLS12,
+	}
+
+	if host.TLSConfig.CASecretRef != nil {
+		if len(creds.CACertPEM) == 0 {
+			return nil, fmt.Errorf("%w: tlsConfig.caSecretRef", ErrCredentialsRequired)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(creds.CACertPEM) {
+			return nil, errors.New("transport: no certificates found in resolved CA bundle")
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if host.TLSConfig.ClientCertSecretRef != nil {
+		if len(creds.ClientCertPEM) == 0 || len(creds.ClientKeyPEM) == 0 {
+			return nil, fmt.Errorf("%w: tlsConfig.clientCertSecretRef", ErrCredentialsRequired)
+		}
+
+		cert, err := tls.X509KeyPair(creds.ClientCertPEM, creds.ClientKeyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("transport: parsing client certificate: %w", err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// basicAuthCredentials implements credentials.PerRPCCredentials to attach
+// an HTTP basic auth header to every RPC.
+type basicAuthCredentials struct {
+	username string
+	password string
+}
+
+func (b basicAuthCredentials) GetRequestMetadata(_ context.Context, _ ...string) (map[string]string, error) {
+	token := base64.StdEncoding.EncodeToString([]byte(b.username + ":" + b.password))
+
+	return map[string]string{
+		"authorization": "Basic " + token,
+	}, nil
+}
+
+// RequireTransportSecurity always returns true: basic auth credentials must
+// never be sent over a cleartext channel, regardless of how the target
+// host's TLSConfig (or lack of one) is set up.
+func (b basicAuthCredentials) RequireTransportSecurity() bool {
+	return true
+}
+
+// proxyContextDialer returns a grpc.WithContextDialer-compatible dialer
+// that establishes the TCP connection to addr by first connecting to
+// proxy and issuing an HTTP CONNECT, per MicrovmProxy's documented role of
+// tunnelling flintlock connections through a corporate proxy.
+func proxyContextDialer(proxy microvm.MicrovmProxy, creds Credentials) (func(ctx context.Context, addr string) (net.Conn, error), error) {
+	proxyTLSConfig, err := proxyTLSConfig(proxy, creds)
+	if err != nil {
+		return nil, err
+	}
+
+	proxyAddr, err := hostPort(proxy.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("transport: parsing proxy endpoint: %w", err)
+	}
+
+	return func(ctx context.Context, addr string) (net.Conn, error) {
+		var (
+			dialer net.Dialer
+			conn   net.Conn
+			err    error
+		)
+
+		if proxyTLSConfig != nil {
+			conn, err = (&tls.Dialer{Config: proxyTLSConfig}).DialContext(ctx, "tcp", proxyAddr)
+		} else {
+			conn, err = dialer.DialContext(ctx, "tcp", proxyAddr)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("transport: dialing proxy %s: %w", proxyAddr, err)
+		}
+
+		if err := connectThroughProxy(conn, addr); err != nil {
+			conn.Close()
+
+			return nil, err
+		}
+
+		return conn, nil
+	}, nil
+}
+
+// connectThroughProxy issues an HTTP CONNECT for addr over conn and
+// consumes the proxy's response, leaving conn positioned to carry the
+// tunnelled TLS/gRPC traffic.
+func connectThroughProxy(conn net.Conn, addr string) error {
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: make(http.Header),
+	}
+
+	if err := req.Write(conn); err != nil {
+		return fmt.Errorf("transport: writing CONNECT request: %w", err)
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		return fmt.Errorf("transport: reading CONNECT response: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("transport: proxy CONNECT to %s failed: %s", addr, resp.Status)
+	}
+
+	return nil
+}
+
+// proxyTLSConfig builds the TLS config used to connect to the proxy
+// itself (as opposed to the tunnelled connection to the flintlock host),
+// or nil if the proxy should be dialed in plaintext.
+func proxyTLSConfig(proxy microvm.MicrovmProxy, creds Credentials) (*tls.Config, error) {
+	if !strings.HasPrefix(proxy.Endpoint, "https://") && proxy.CACertSecretRef == nil && proxy.ClientCertSecretRef == nil {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12} //nolint:gosec // MinVersion set explicitly above
+
+	if proxy.CACertSecretRef != nil {
+		if len(creds.ProxyCACertPEM) == 0 {
+			return nil, fmt.Errorf("%w: proxy.caCertSecretRef", ErrCredentialsRequired)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(creds.ProxyCACertPEM) {
+			return nil, errors.New("transport: no certificates found in resolved proxy CA bundle")
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if proxy.ClientCertSecretRef != nil {
+		if len(creds.ProxyClientCertPEM) == 0 || len(creds.ProxyClientKeyPEM) == 0 {
+			return nil, fmt.Errorf("%w: proxy.clientCertSecretRef", ErrCredentialsRequired)
+		}
+
+		cert, err := tls.X509KeyPair(creds.ProxyClientCertPEM, creds.ProxyClientKeyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("transport: parsing proxy client certificate: %w", err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// hostPort strips an optional "http://" or "https://" scheme from
+// endpoint, returning the bare host:port that net.Dial expects.
+func hostPort(endpoint string) (string, error) {
+	if !strings.Contains(endpoint, "://") {
+		return endpoint, nil
+	}
+
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	return parsed.Host, nil
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2022 Weaveworks.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transport
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/weaveworks-liquidmetal/controller-pkg/types/microvm"
+)
+
+func TestBasicAuthCredentialsRequiresTransportSecurity(t *testing.T) {
+	// Regardless of whether a TLSConfig is present, Basic Auth credentials
+	// must never be sent over a cleartext channel.
+	creds := basicAuthCredentials{username: "u", password: "p"}
+
+	if !creds.RequireTransportSecurity() {
+		t.Fatal("RequireTransportSecurity() = false, want true for basic auth credentials")
+	}
+}
+
+func TestDialOptionsRequiresResolvedBasicAuth(t *testing.T) {
+	host := microvm.Host{
+		Endpoint:  "flintlock.example.com:9090",
+		BasicAuth: &microvm.BasicAuth{SecretRef: microvm.SecretRef{Name: "creds"}},
+	}
+
+	_, err := DialOptions(host, nil, Credentials{})
+	if !errors.Is(err, ErrCredentialsRequired) {
+		t.Fatalf("DialOptions() error = %v, want %v", err, ErrCredentialsRequired)
+	}
+}
+
+func TestDialOptionsPlaintextNoProxy(t *testing.T) {
+	host := microvm.Host{Endpoint: "flintlock.example.com:9090"}
+
+	opts, err := DialOptions(host, nil, Credentials{})
+	if err != nil {
+		t.Fatalf("DialOptions() error = %v", err)
+	}
+
+	if len(opts) != 1 {
+		t.Fatalf("DialOptions() returned %d options, want 1", len(opts))
+	}
+}
+
+func TestDialOptionsRequiresResolvedProxyCA(t *testing.T) {
+	host := microvm.Host{Endpoint: "flintlock.example.com:9090"}
+	proxy := microvm.MicrovmProxy{
+		Endpoint:        "https://proxy.example.com:3128",
+		CACertSecretRef: &microvm.SecretRef{Name: "proxy-ca"},
+	}
+
+	_, err := DialOptions(host, &proxy, Credentials{})
+	if !errors.Is(err, ErrCredentialsRequired) {
+		t.Fatalf("DialOptions() error = %v, want %v", err, ErrCredentialsRequired)
+	}
+}